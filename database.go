@@ -87,6 +87,41 @@ func (d *Database) WithTx(fn func(*TxDatabase) error) error {
 	return tx.Wrap(func() error { return fn(tx) })
 }
 
+// Primary returns a *DSL scoped to this Database's primary connection, when
+// Db is a *Resolver. This forces queries built from it to bypass replica
+// routing, even reads. On a Database that isn't backed by a Resolver, it
+// simply returns this Database's own DSL.
+func (d *Database) Primary() *DSL {
+	if r, ok := d.Db.(*Resolver); ok {
+		return scopedDSL(d.DSL, r.primary)
+	}
+	return d.DSL
+}
+
+// Replica returns a *DSL scoped to a single, load-balanced replica
+// connection, when Db is a *Resolver. This forces queries built from it to
+// hit a replica even if they would otherwise be routed to primary (e.g.
+// ExecContext). If no replica is currently healthy, or Db isn't backed by a
+// Resolver, it falls back to this Database's own DSL.
+func (d *Database) Replica() *DSL {
+	if r, ok := d.Db.(*Resolver); ok {
+		if rep := r.pickReplica(); rep != nil {
+			return scopedDSL(d.DSL, rep.db)
+		}
+	}
+	return d.DSL
+}
+
+// scopedDSL returns a new DSL that runs against sql but keeps base's
+// dialect, Logger, and Hooks, so routing overrides like Primary/Replica
+// don't silently drop any Logger/Hooks configured on the outer Database.
+func scopedDSL(base *DSL, sql SQL) *DSL {
+	d := newDSL(base.dialect, sql)
+	d.logger = base.logger
+	d.hooks = base.hooks
+	return d
+}
+
 // A wrapper around a sql.Tx and works the same way as Database
 type (
 	// Interface for sql.Tx, an interface is used so you can use with other
@@ -99,6 +134,8 @@ type (
 	TxDatabase struct {
 		*DSL
 		Tx SQLTx
+
+		savepointSeq int32
 	}
 )
 
@@ -112,14 +149,24 @@ func NewTx(dialect string, tx SQLTx) *TxDatabase {
 
 // COMMIT the transaction
 func (td *TxDatabase) Commit() error {
-	td.Trace("COMMIT", "")
-	return td.Tx.Commit()
+	ctx, err := td.hooks.BeforeQuery(context.Background(), "COMMIT", "")
+	if err != nil {
+		return err
+	}
+	err = td.Tx.Commit()
+	td.hooks.AfterQuery(ctx, "COMMIT", "", nil, -1, err)
+	return err
 }
 
 // ROLLBACK the transaction
 func (td *TxDatabase) Rollback() error {
-	td.Trace("ROLLBACK", "")
-	return td.Tx.Rollback()
+	ctx, err := td.hooks.BeforeQuery(context.Background(), "ROLLBACK", "")
+	if err != nil {
+		return err
+	}
+	err = td.Tx.Rollback()
+	td.hooks.AfterQuery(ctx, "ROLLBACK", "", nil, -1, err)
+	return err
 }
 
 // A helper method that will automatically COMMIT or ROLLBACK once the supplied function is done executing
@@ -24,6 +24,7 @@ type (
 		sql     SQL
 		qf      exec.QueryFactory
 		qfOnce  sync.Once
+		hooks   Hooks
 	}
 )
 
@@ -56,13 +57,15 @@ type (
 //          }
 //          fmt.Printf("%+v", ids)
 func newDSL(dialect string, sql SQL) *DSL {
-	return &DSL{
+	d := &DSL{
 		logger:  nil,
 		dialect: dialect,
 		sql:     sql,
 		qf:      nil,
 		qfOnce:  sync.Once{},
 	}
+	d.hooks = traceHook{d: d}
+	return d
 }
 
 // returns this databases dialect
@@ -137,8 +140,27 @@ func (d *DSL) Exec(query string, args ...interface{}) (sql.Result, error) {
 //
 // args...: for any placeholder parameters in the query
 func (d *DSL) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
-	d.Trace("EXEC", query, args...)
-	return d.sql.ExecContext(ctx, query, args...)
+	return d.execOp(ctx, "EXEC", query, args...)
+}
+
+// execOp runs query through the BeforeQuery/AfterQuery hook pipeline under
+// the given op name and executes it. Callers that need ExecContext's
+// behavior under a different, more specific op name (e.g. savepoint
+// boundaries) use this directly instead of duplicating the hook wiring.
+func (d *DSL) execOp(ctx context.Context, op, query string, args ...interface{}) (sql.Result, error) {
+	ctx, err := d.hooks.BeforeQuery(ctx, op, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	res, err := d.sql.ExecContext(ctx, query, args...)
+	rowsAffected := int64(-1)
+	if err == nil && res != nil {
+		if n, raErr := res.RowsAffected(); raErr == nil {
+			rowsAffected = n
+		}
+	}
+	d.hooks.AfterQuery(ctx, op, query, args, rowsAffected, err)
+	return res, err
 }
 
 // Can be used to prepare a query.
@@ -196,8 +218,13 @@ func (d *DSL) Prepare(query string) (*sql.Stmt, error) {
 //
 // query: The SQL statement to prepare.
 func (d *DSL) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
-	d.Trace("PREPARE", query)
-	return d.sql.PrepareContext(ctx, query)
+	ctx, err := d.hooks.BeforeQuery(ctx, "PREPARE", query)
+	if err != nil {
+		return nil, err
+	}
+	stmt, err := d.sql.PrepareContext(ctx, query)
+	d.hooks.AfterQuery(ctx, "PREPARE", query, nil, -1, err)
+	return stmt, err
 }
 
 // Used to query for multiple rows.
@@ -249,8 +276,13 @@ func (d *DSL) Query(query string, args ...interface{}) (*sql.Rows, error) {
 //
 // args...: for any placeholder parameters in the query
 func (d *DSL) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
-	d.Trace("QUERY", query, args...)
-	return d.sql.QueryContext(ctx, query, args...)
+	ctx, err := d.hooks.BeforeQuery(ctx, "QUERY", query, args...)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := d.sql.QueryContext(ctx, query, args...)
+	d.hooks.AfterQuery(ctx, "QUERY", query, args, -1, err)
+	return rows, err
 }
 
 // Used to query for a single row.
@@ -290,8 +322,23 @@ func (d *DSL) QueryRow(query string, args ...interface{}) *sql.Row {
 //
 // args...: for any placeholder parameters in the query
 func (d *DSL) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
-	d.Trace("QUERY ROW", query, args...)
-	return d.sql.QueryRowContext(ctx, query, args...)
+	ctx, err := d.hooks.BeforeQuery(ctx, "QUERY ROW", query, args...)
+	if err != nil {
+		// *sql.Row has no exported constructor for a pre-set error, and
+		// database/sql only ever consults ctx.Err() (never
+		// context.Cause(ctx)), so the best we can do without a real query
+		// is cancel ctx: the returned row's Scan/Err will report
+		// context.Canceled rather than err itself. Hooks observing
+		// AfterQuery below still see the real rejection reason, so
+		// BeforeQuery rejections of QueryRowContext remain visible to
+		// instrumentation even though the row itself can't carry err.
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		cancel()
+	}
+	row := d.sql.QueryRowContext(ctx, query, args...)
+	d.hooks.AfterQuery(ctx, "QUERY ROW", query, args, -1, err)
+	return row
 }
 
 func (d *DSL) queryFactory() exec.QueryFactory {
@@ -322,7 +369,13 @@ func (d *DSL) ScanStructs(i interface{}, query string, args ...interface{}) erro
 //
 // args...: for any placeholder parameters in the query
 func (d *DSL) ScanStructsContext(ctx context.Context, i interface{}, query string, args ...interface{}) error {
-	return d.queryFactory().FromSQL(query, args...).ScanStructsContext(ctx, i)
+	ctx, err := d.hooks.BeforeQuery(ctx, "SCAN STRUCTS", query, args...)
+	if err != nil {
+		return err
+	}
+	err = d.queryFactory().FromSQL(query, args...).ScanStructsContext(ctx, i)
+	d.hooks.AfterQuery(ctx, "SCAN STRUCTS", query, args, -1, err)
+	return err
 }
 
 // Queries the database using the supplied query, and args and uses CrudExec.ScanStruct to scan the results into a
@@ -346,7 +399,13 @@ func (d *DSL) ScanStruct(i interface{}, query string, args ...interface{}) (bool
 //
 // args...: for any placeholder parameters in the query
 func (d *DSL) ScanStructContext(ctx context.Context, i interface{}, query string, args ...interface{}) (bool, error) {
-	return d.queryFactory().FromSQL(query, args...).ScanStructContext(ctx, i)
+	ctx, err := d.hooks.BeforeQuery(ctx, "SCAN STRUCT", query, args...)
+	if err != nil {
+		return false, err
+	}
+	found, err := d.queryFactory().FromSQL(query, args...).ScanStructContext(ctx, i)
+	d.hooks.AfterQuery(ctx, "SCAN STRUCT", query, args, -1, err)
+	return found, err
 }
 
 // Queries the database using the supplied query, and args and uses CrudExec.ScanVals to scan the results into a slice
@@ -370,7 +429,13 @@ func (d *DSL) ScanVals(i interface{}, query string, args ...interface{}) error {
 //
 // args...: for any placeholder parameters in the query
 func (d *DSL) ScanValsContext(ctx context.Context, i interface{}, query string, args ...interface{}) error {
-	return d.queryFactory().FromSQL(query, args...).ScanValsContext(ctx, i)
+	ctx, err := d.hooks.BeforeQuery(ctx, "SCAN VALS", query, args...)
+	if err != nil {
+		return err
+	}
+	err = d.queryFactory().FromSQL(query, args...).ScanValsContext(ctx, i)
+	d.hooks.AfterQuery(ctx, "SCAN VALS", query, args, -1, err)
+	return err
 }
 
 // Queries the database using the supplied query, and args and uses CrudExec.ScanVal to scan the results into a
@@ -394,5 +459,11 @@ func (d *DSL) ScanVal(i interface{}, query string, args ...interface{}) (bool, e
 //
 // args...: for any placeholder parameters in the query
 func (d *DSL) ScanValContext(ctx context.Context, i interface{}, query string, args ...interface{}) (bool, error) {
-	return d.queryFactory().FromSQL(query, args...).ScanValContext(ctx, i)
+	ctx, err := d.hooks.BeforeQuery(ctx, "SCAN VAL", query, args...)
+	if err != nil {
+		return false, err
+	}
+	found, err := d.queryFactory().FromSQL(query, args...).ScanValContext(ctx, i)
+	d.hooks.AfterQuery(ctx, "SCAN VAL", query, args, -1, err)
+	return found, err
 }
@@ -0,0 +1,47 @@
+package goqu
+
+import "context"
+
+// Hooks lets callers observe every query a DSL executes. Implementations
+// can use BeforeQuery to inject context (e.g. start a span, attach a
+// deadline) or abort the query outright, and AfterQuery to record metrics,
+// logs, or slow-query alerts. Unlike Logger, Hooks sees the operation and
+// outcome of every call, not just a formatted trace line.
+type Hooks interface {
+	// BeforeQuery is called before op is executed with sqlString and args.
+	// The returned context replaces ctx for the remainder of the call; a
+	// non-nil error aborts the query before it reaches the database and is
+	// returned to the caller. The one exception is QueryRowContext: since
+	// *sql.Row carries its own error with no exported way to set it, a
+	// rejection there still stops the query from reaching the database, but
+	// the row's Scan/Err reports context.Canceled rather than this error.
+	BeforeQuery(ctx context.Context, op, sqlString string, args ...interface{}) (context.Context, error)
+	// AfterQuery is called once op has completed. rowsAffected is -1 when
+	// not applicable (e.g. QueryContext, or QueryRowContext whose error is
+	// only known once the row is scanned).
+	AfterQuery(ctx context.Context, op, sqlString string, args []interface{}, rowsAffected int64, err error)
+}
+
+// traceHook reimplements the Logger-based Trace behavior as a Hooks
+// implementation. It is the default Hooks installed by newDSL, so code that
+// only calls Logger keeps its trace lines for EXEC, PREPARE, QUERY, QUERY ROW,
+// COMMIT and ROLLBACK; the Scan* methods and savepoint operations now also
+// produce trace lines of their own.
+type traceHook struct {
+	d *DSL
+}
+
+func (h traceHook) BeforeQuery(ctx context.Context, op, sqlString string, args ...interface{}) (context.Context, error) {
+	h.d.Trace(op, sqlString, args...)
+	return ctx, nil
+}
+
+func (h traceHook) AfterQuery(context.Context, string, string, []interface{}, int64, error) {}
+
+// Hooks sets the Hooks implementation used to observe every query run
+// through this DSL, replacing the built-in Logger-based tracing installed
+// by default. Call Logger from within your own BeforeQuery/AfterQuery if you
+// want to keep trace logging alongside your custom hooks.
+func (d *DSL) Hooks(h Hooks) {
+	d.hooks = h
+}
@@ -0,0 +1,140 @@
+package goqu
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type (
+	loadFileConfig struct {
+		splitStatements bool
+	}
+
+	// LoadFileOption configures the behavior of LoadFileContext.
+	LoadFileOption func(*loadFileConfig)
+)
+
+// SplitStatements causes LoadFileContext to naively split the file's
+// contents on `;` at statement boundaries and execute each statement in
+// sequence, rather than sending the whole file as a single statement. Use
+// this for dialects that can't execute multi-statement strings in a single
+// call, such as mattn/go-sqlite3.
+func SplitStatements() LoadFileOption {
+	return func(c *loadFileConfig) { c.splitStatements = true }
+}
+
+// LoadFile reads the file at path and executes its contents against the
+// underlying SQL connection. It's useful for seeding schemas, loading
+// fixtures, or applying ad-hoc migrations in tests without pulling in a
+// migration library.
+func (d *DSL) LoadFile(path string) (sql.Result, error) {
+	return d.LoadFileContext(context.Background(), path)
+}
+
+// LoadFileContext is like LoadFile but accepts a context and options, such
+// as SplitStatements.
+func (d *DSL) LoadFileContext(ctx context.Context, path string, opts ...LoadFileOption) (sql.Result, error) {
+	cfg := &loadFileConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(abs)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	d.Trace("LOAD FILE", abs)
+	contents, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	if !cfg.splitStatements {
+		return d.ExecContext(ctx, string(contents))
+	}
+	return d.execStatements(ctx, splitStatements(string(contents)))
+}
+
+// txBeginner is implemented by the SQLDatabase underlying a plain Database's
+// DSL, but not by the SQLTx underlying a TxDatabase's DSL (which is already
+// inside a transaction).
+type txBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// execStatements executes each statement in sequence within a single
+// transaction, stopping and rolling back on the first error encountered.
+// The sql.Result of the final statement is returned. If d is already
+// running inside a transaction (e.g. it's a TxDatabase's DSL), every
+// statement already shares that transaction, so it's run directly: the
+// caller's surrounding Wrap/WithTx controls the commit/rollback boundary.
+func (d *DSL) execStatements(ctx context.Context, statements []string) (sql.Result, error) {
+	beginner, ok := d.sql.(txBeginner)
+	if !ok {
+		return d.execStatementsDirect(ctx, statements)
+	}
+	tx, err := beginner.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := d.execStatementsTx(ctx, tx, statements)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (d *DSL) execStatementsDirect(ctx context.Context, statements []string) (sql.Result, error) {
+	var res sql.Result
+	for _, stmt := range statements {
+		var err error
+		res, err = d.ExecContext(ctx, stmt)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// execStatementsTx runs statements through a DSL scoped to tx, so each
+// statement still goes through d's Hooks (and, by default, Logger-based
+// tracing) the same way ExecContext does, instead of bypassing them by
+// calling tx.ExecContext directly.
+func (d *DSL) execStatementsTx(ctx context.Context, tx *sql.Tx, statements []string) (sql.Result, error) {
+	txDSL := scopedDSL(d, tx)
+	var res sql.Result
+	for _, stmt := range statements {
+		var err error
+		res, err = txDSL.ExecContext(ctx, stmt)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// splitStatements naively splits contents on `;` at statement boundaries,
+// trims whitespace, and drops empty statements.
+func splitStatements(contents string) []string {
+	raw := strings.Split(contents, ";")
+	statements := make([]string, 0, len(raw))
+	for _, stmt := range raw {
+		stmt = strings.TrimSpace(stmt)
+		if stmt == "" {
+			continue
+		}
+		statements = append(statements, stmt)
+	}
+	return statements
+}
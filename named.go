@@ -0,0 +1,208 @@
+package goqu
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Rebind converts a query built with standard `?` positional placeholders
+// into the placeholder style used by this DSL's dialect (`?` for mysql and
+// sqlite3, `$N` for postgres, `@pN` for sqlserver, `:N` for oracle). `?`
+// characters inside single or double quoted string literals are left alone.
+func (d *DSL) Rebind(query string) string {
+	return rebind(d.dialect, query)
+}
+
+// NamedExec parses query for sqlx-style `:name` placeholders, binds them
+// from arg (a map[string]interface{} or a struct using `db` struct tags) in
+// the order they appear, rebinds the query to this DSL's dialect, and
+// executes it.
+func (d *DSL) NamedExec(query string, arg interface{}) (sql.Result, error) {
+	return d.NamedExecContext(context.Background(), query, arg)
+}
+
+// NamedExecContext is like NamedExec but accepts a context.
+func (d *DSL) NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error) {
+	boundQuery, args, err := d.compileNamed(query, arg)
+	if err != nil {
+		return nil, err
+	}
+	return d.ExecContext(ctx, boundQuery, args...)
+}
+
+// NamedQuery parses query for sqlx-style `:name` placeholders, binds them
+// from arg (a map[string]interface{} or a struct using `db` struct tags) in
+// the order they appear, rebinds the query to this DSL's dialect, and runs
+// it, returning the resulting rows.
+func (d *DSL) NamedQuery(query string, arg interface{}) (*sql.Rows, error) {
+	return d.NamedQueryContext(context.Background(), query, arg)
+}
+
+// NamedQueryContext is like NamedQuery but accepts a context.
+func (d *DSL) NamedQueryContext(ctx context.Context, query string, arg interface{}) (*sql.Rows, error) {
+	boundQuery, args, err := d.compileNamed(query, arg)
+	if err != nil {
+		return nil, err
+	}
+	return d.QueryContext(ctx, boundQuery, args...)
+}
+
+// compileNamed rewrites query's `:name` placeholders into `?`, resolves arg
+// values for each name, and rebinds the result to this DSL's dialect.
+func (d *DSL) compileNamed(query string, arg interface{}) (string, []interface{}, error) {
+	unbound, names := compileNamedQuery(query)
+	args, err := bindNamed(names, arg)
+	if err != nil {
+		return "", nil, err
+	}
+	return d.Rebind(unbound), args, nil
+}
+
+// compileNamedQuery rewrites every `:name` placeholder in query into `?`,
+// returning the rewritten query along with the names in the order they were
+// encountered. A `::` (used by Postgres for type casts) is left untouched,
+// as is anything inside single or double quoted string literals.
+func compileNamedQuery(query string) (string, []string) {
+	var names []string
+	var sb strings.Builder
+	runes := []rune(query)
+	inQuote := rune(0)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		if inQuote != 0 {
+			sb.WriteRune(c)
+			if c == inQuote {
+				inQuote = 0
+			}
+			continue
+		}
+		switch {
+		case c == '\'' || c == '"':
+			inQuote = c
+			sb.WriteRune(c)
+		case c == ':' && i+1 < len(runes) && runes[i+1] == ':':
+			sb.WriteRune(c)
+			sb.WriteRune(runes[i+1])
+			i++
+		case c == ':':
+			j := i + 1
+			for j < len(runes) && isNameRune(runes[j]) {
+				j++
+			}
+			if j == i+1 {
+				sb.WriteRune(c)
+				continue
+			}
+			names = append(names, string(runes[i+1:j]))
+			sb.WriteByte('?')
+			i = j - 1
+		default:
+			sb.WriteRune(c)
+		}
+	}
+	return sb.String(), names
+}
+
+func isNameRune(r rune) bool {
+	return r == '_' ||
+		(r >= 'a' && r <= 'z') ||
+		(r >= 'A' && r <= 'Z') ||
+		(r >= '0' && r <= '9')
+}
+
+// bindNamed resolves each name to a value from arg, which must be either a
+// map[string]interface{} or a struct (or pointer to one), returning the
+// values in the same order as names.
+func bindNamed(names []string, arg interface{}) ([]interface{}, error) {
+	if m, ok := arg.(map[string]interface{}); ok {
+		args := make([]interface{}, len(names))
+		for i, name := range names {
+			v, ok := m[name]
+			if !ok {
+				return nil, fmt.Errorf("goqu: missing named parameter %q", name)
+			}
+			args[i] = v
+		}
+		return args, nil
+	}
+	return bindNamedStruct(names, arg)
+}
+
+// bindNamedStruct resolves each name to a struct field, matching `db`
+// struct tags the same way ScanStructs does, falling back to the lowercased
+// field name when no tag is present.
+func bindNamedStruct(names []string, arg interface{}) ([]interface{}, error) {
+	val := reflect.ValueOf(arg)
+	for val.Kind() == reflect.Ptr {
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("goqu: named parameters must be bound from a map[string]interface{} or a struct, got %T", arg)
+	}
+	fields := make(map[string]reflect.Value, val.NumField())
+	t := val.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("db")
+		if tag == "-" {
+			continue
+		}
+		if tag == "" {
+			tag = strings.ToLower(f.Name)
+		}
+		fields[tag] = val.Field(i)
+	}
+	args := make([]interface{}, len(names))
+	for i, name := range names {
+		fv, ok := fields[name]
+		if !ok {
+			return nil, fmt.Errorf("goqu: missing named parameter %q", name)
+		}
+		args[i] = fv.Interface()
+	}
+	return args, nil
+}
+
+// rebind rewrites a `?`-placeholder query into dialect's native placeholder
+// style, leaving `?` characters inside quoted string literals untouched.
+func rebind(dialect, query string) string {
+	var marker func(n int) string
+	switch dialect {
+	case "postgres", "redshift":
+		marker = func(n int) string { return "$" + strconv.Itoa(n) }
+	case "sqlserver":
+		marker = func(n int) string { return "@p" + strconv.Itoa(n) }
+	case "oracle":
+		marker = func(n int) string { return ":" + strconv.Itoa(n) }
+	default:
+		return query
+	}
+	var sb strings.Builder
+	inQuote := byte(0)
+	n := 0
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		if inQuote != 0 {
+			sb.WriteByte(c)
+			if c == inQuote {
+				inQuote = 0
+			}
+			continue
+		}
+		switch c {
+		case '\'', '"':
+			inQuote = c
+			sb.WriteByte(c)
+		case '?':
+			n++
+			sb.WriteString(marker(n))
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,193 @@
+package goqu
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompileNamedQuery(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		wantQuery string
+		wantNames []string
+	}{
+		{
+			name:      "no placeholders",
+			query:     "SELECT * FROM foo",
+			wantQuery: "SELECT * FROM foo",
+			wantNames: nil,
+		},
+		{
+			name:      "single placeholder",
+			query:     "SELECT * FROM foo WHERE id = :id",
+			wantQuery: "SELECT * FROM foo WHERE id = ?",
+			wantNames: []string{"id"},
+		},
+		{
+			name:      "multiple placeholders in order",
+			query:     "SELECT * FROM foo WHERE id = :id AND name = :name",
+			wantQuery: "SELECT * FROM foo WHERE id = ? AND name = ?",
+			wantNames: []string{"id", "name"},
+		},
+		{
+			name:      "postgres cast is left alone",
+			query:     "SELECT id::text FROM foo WHERE id = :id",
+			wantQuery: "SELECT id::text FROM foo WHERE id = ?",
+			wantNames: []string{"id"},
+		},
+		{
+			name:      "colon inside single quoted literal is left alone",
+			query:     "SELECT * FROM foo WHERE name = 'a:b' AND id = :id",
+			wantQuery: "SELECT * FROM foo WHERE name = 'a:b' AND id = ?",
+			wantNames: []string{"id"},
+		},
+		{
+			name:      "colon inside double quoted literal is left alone",
+			query:     `SELECT * FROM foo WHERE name = "a:b" AND id = :id`,
+			wantQuery: `SELECT * FROM foo WHERE name = "a:b" AND id = ?`,
+			wantNames: []string{"id"},
+		},
+		{
+			name:      "trailing colon with no name is left alone",
+			query:     "SELECT * FROM foo WHERE id = 1:",
+			wantQuery: "SELECT * FROM foo WHERE id = 1:",
+			wantNames: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotQuery, gotNames := compileNamedQuery(tt.query)
+			if gotQuery != tt.wantQuery {
+				t.Errorf("query = %q, want %q", gotQuery, tt.wantQuery)
+			}
+			if !reflect.DeepEqual(gotNames, tt.wantNames) {
+				t.Errorf("names = %v, want %v", gotNames, tt.wantNames)
+			}
+		})
+	}
+}
+
+func TestBindNamed_Map(t *testing.T) {
+	args, err := bindNamed([]string{"id", "name"}, map[string]interface{}{
+		"id":   1,
+		"name": "alice",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := []interface{}{1, "alice"}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("args = %v, want %v", args, want)
+	}
+}
+
+func TestBindNamed_MapMissingKey(t *testing.T) {
+	_, err := bindNamed([]string{"id"}, map[string]interface{}{})
+	if err == nil {
+		t.Fatal("expected an error for a missing named parameter")
+	}
+}
+
+func TestBindNamedStruct(t *testing.T) {
+	type user struct {
+		ID      int    `db:"id"`
+		Name    string `db:"name"`
+		private string
+		Ignored string `db:"-"`
+		Untaged string
+	}
+	u := user{ID: 1, Name: "alice", Untaged: "x"}
+	args, err := bindNamedStruct([]string{"id", "name", "untaged"}, u)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := []interface{}{1, "alice", "x"}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("args = %v, want %v", args, want)
+	}
+}
+
+func TestBindNamedStruct_Pointer(t *testing.T) {
+	type user struct {
+		ID int `db:"id"`
+	}
+	u := &user{ID: 1}
+	args, err := bindNamedStruct([]string{"id"}, u)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := []interface{}{1}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("args = %v, want %v", args, want)
+	}
+}
+
+func TestBindNamedStruct_MissingField(t *testing.T) {
+	type user struct {
+		ID int `db:"id"`
+	}
+	_, err := bindNamedStruct([]string{"name"}, user{ID: 1})
+	if err == nil {
+		t.Fatal("expected an error for a missing named parameter")
+	}
+}
+
+func TestBindNamedStruct_NotAStruct(t *testing.T) {
+	_, err := bindNamedStruct([]string{"id"}, 1)
+	if err == nil {
+		t.Fatal("expected an error when arg is not a struct or map")
+	}
+}
+
+func TestRebind(t *testing.T) {
+	tests := []struct {
+		dialect string
+		query   string
+		want    string
+	}{
+		{
+			dialect: "postgres",
+			query:   "SELECT * FROM foo WHERE id = ? AND name = ?",
+			want:    "SELECT * FROM foo WHERE id = $1 AND name = $2",
+		},
+		{
+			dialect: "redshift",
+			query:   "SELECT * FROM foo WHERE id = ?",
+			want:    "SELECT * FROM foo WHERE id = $1",
+		},
+		{
+			dialect: "sqlserver",
+			query:   "SELECT * FROM foo WHERE id = ? AND name = ?",
+			want:    "SELECT * FROM foo WHERE id = @p1 AND name = @p2",
+		},
+		{
+			dialect: "oracle",
+			query:   "SELECT * FROM foo WHERE id = ?",
+			want:    "SELECT * FROM foo WHERE id = :1",
+		},
+		{
+			dialect: "mysql",
+			query:   "SELECT * FROM foo WHERE id = ?",
+			want:    "SELECT * FROM foo WHERE id = ?",
+		},
+		{
+			dialect: "sqlite3",
+			query:   "SELECT * FROM foo WHERE id = ?",
+			want:    "SELECT * FROM foo WHERE id = ?",
+		},
+		{
+			dialect: "postgres",
+			query:   "SELECT * FROM foo WHERE name = '?' AND id = ?",
+			want:    "SELECT * FROM foo WHERE name = '?' AND id = $1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dialect, func(t *testing.T) {
+			got := rebind(tt.dialect, tt.query)
+			if got != tt.want {
+				t.Errorf("rebind(%q, %q) = %q, want %q", tt.dialect, tt.query, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,265 @@
+package goqu
+
+import (
+	"context"
+	"database/sql"
+	"math/rand"
+	"sync/atomic"
+	"time"
+)
+
+type (
+	// LoadBalancer selects which of the currently healthy replicas should
+	// serve the next read. Implementations are called with the original
+	// indices (as passed to NewResolver) of the currently healthy replicas,
+	// and must return one of the values from healthy. Passing identity
+	// rather than just a count lets policies like WeightedLoadBalancer keep
+	// each replica's configured weight attached to it as other replicas are
+	// demoted and restored by health checks.
+	LoadBalancer interface {
+		Next(healthy []int) int
+	}
+
+	// RoundRobinLoadBalancer cycles through replicas in order. It is the
+	// default LoadBalancer used by a Resolver.
+	RoundRobinLoadBalancer struct {
+		counter uint64
+	}
+
+	// RandomLoadBalancer picks a replica uniformly at random.
+	RandomLoadBalancer struct{}
+
+	// WeightedLoadBalancer picks a replica with probability proportional to
+	// its configured weight. weights are matched to replicas by position, in
+	// the order replicas were passed to NewResolver.
+	WeightedLoadBalancer struct {
+		weights []int
+	}
+)
+
+// Next returns the next replica index from healthy, cycling through them in order.
+func (lb *RoundRobinLoadBalancer) Next(healthy []int) int {
+	if len(healthy) == 0 {
+		return 0
+	}
+	i := atomic.AddUint64(&lb.counter, 1)
+	return healthy[int(i-1)%len(healthy)]
+}
+
+// Next returns a replica index chosen uniformly at random from healthy.
+func (lb *RandomLoadBalancer) Next(healthy []int) int {
+	if len(healthy) == 0 {
+		return 0
+	}
+	return healthy[rand.Intn(len(healthy))]
+}
+
+// NewWeightedLoadBalancer creates a WeightedLoadBalancer using the given
+// per-replica weights. weights must be in the same order as the replicas
+// passed to NewResolver.
+func NewWeightedLoadBalancer(weights ...int) *WeightedLoadBalancer {
+	return &WeightedLoadBalancer{weights: weights}
+}
+
+// Next returns a replica index, from healthy, chosen with probability
+// proportional to its configured weight. Because healthy carries each
+// candidate's original index (its position as passed to NewResolver), a
+// replica keeps its own weight even when other replicas have been demoted
+// or restored by health checks. A replica beyond the configured weights
+// (i.e. more replicas were passed to NewResolver than weights to
+// NewWeightedLoadBalancer) defaults to a weight of 1 rather than being
+// silently excluded from the pool. If the resulting weights don't add up to
+// a positive total, it falls back to picking uniformly at random.
+func (lb *WeightedLoadBalancer) Next(healthy []int) int {
+	if len(healthy) == 0 {
+		return 0
+	}
+	total := 0
+	for _, idx := range healthy {
+		total += lb.weight(idx)
+	}
+	if total <= 0 {
+		return healthy[rand.Intn(len(healthy))]
+	}
+	r := rand.Intn(total)
+	for _, idx := range healthy {
+		r -= lb.weight(idx)
+		if r < 0 {
+			return idx
+		}
+	}
+	return healthy[len(healthy)-1]
+}
+
+// weight returns the configured weight for replica i, defaulting to 1 if i
+// is beyond the weights passed to NewWeightedLoadBalancer.
+func (lb *WeightedLoadBalancer) weight(i int) int {
+	if i < len(lb.weights) {
+		return lb.weights[i]
+	}
+	return 1
+}
+
+// pinger is implemented by SQLDatabase connections that support health
+// checks (e.g. a wrapped *sql.DB). Connections that don't implement it are
+// always considered healthy.
+type pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// replica tracks a single read connection and whether it is currently
+// considered healthy.
+type replica struct {
+	db      SQLDatabase
+	healthy int32 // accessed atomically; 1 healthy, 0 demoted
+}
+
+func (r *replica) isHealthy() bool {
+	return atomic.LoadInt32(&r.healthy) == 1
+}
+
+func (r *replica) setHealthy(healthy bool) {
+	v := int32(0)
+	if healthy {
+		v = 1
+	}
+	atomic.StoreInt32(&r.healthy, v)
+}
+
+// Resolver wraps a primary SQLDatabase and a pool of read replicas, and
+// itself implements SQLDatabase so it can be dropped in anywhere a
+// SQLDatabase is expected. Write-style calls (ExecContext, PrepareContext,
+// Begin, BeginTx) are always sent to primary; read-style calls
+// (QueryContext, QueryRowContext) are routed to a healthy replica chosen by
+// the configured LoadBalancer, falling back to primary if no replica is
+// currently healthy.
+type Resolver struct {
+	primary  SQLDatabase
+	replicas []*replica
+	lb       LoadBalancer
+
+	healthCheckStop chan struct{}
+}
+
+// NewResolver creates a *Database that transparently splits reads and
+// writes across a primary connection and a pool of read replicas. dialect
+// must be the dialect registered for primary and every replica. By default
+// reads are spread across replicas using a RoundRobinLoadBalancer; use
+// Resolver.LoadBalancer to change the policy and Resolver.StartHealthChecks
+// to enable automatic failover.
+func NewResolver(dialect string, primary SQLDatabase, replicas ...SQLDatabase) *Database {
+	r := &Resolver{
+		primary: primary,
+		lb:      &RoundRobinLoadBalancer{},
+	}
+	for _, rep := range replicas {
+		r.replicas = append(r.replicas, &replica{db: rep, healthy: 1})
+	}
+	return newDatabase(dialect, r)
+}
+
+// LoadBalancer sets the policy used to choose among healthy replicas.
+func (r *Resolver) LoadBalancer(lb LoadBalancer) {
+	r.lb = lb
+}
+
+// StartHealthChecks begins pinging every replica that implements pinger on
+// the given interval, demoting any replica whose ping fails and restoring it
+// once a ping succeeds again. Call StopHealthChecks to stop.
+func (r *Resolver) StartHealthChecks(interval time.Duration) {
+	stop := make(chan struct{})
+	r.healthCheckStop = stop
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				r.checkHealth()
+			}
+		}
+	}()
+}
+
+// StopHealthChecks stops a health check loop started by StartHealthChecks.
+func (r *Resolver) StopHealthChecks() {
+	if r.healthCheckStop != nil {
+		close(r.healthCheckStop)
+		r.healthCheckStop = nil
+	}
+}
+
+func (r *Resolver) checkHealth() {
+	for _, rep := range r.replicas {
+		p, ok := rep.db.(pinger)
+		if !ok {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		err := p.PingContext(ctx)
+		cancel()
+		rep.setHealthy(err == nil)
+	}
+}
+
+// pickReplica returns a healthy replica chosen by the configured
+// LoadBalancer, or nil if none are currently healthy. It passes the
+// LoadBalancer the original index of each healthy replica, rather than just
+// a count, so policies like WeightedLoadBalancer apply the right replica's
+// weight even after demotions/restorations have changed which positions in
+// r.replicas are currently healthy.
+func (r *Resolver) pickReplica() *replica {
+	var healthy []int
+	for i, rep := range r.replicas {
+		if rep.isHealthy() {
+			healthy = append(healthy, i)
+		}
+	}
+	if len(healthy) == 0 {
+		return nil
+	}
+	return r.replicas[r.lb.Next(healthy)]
+}
+
+func (r *Resolver) reader() SQLDatabase {
+	if rep := r.pickReplica(); rep != nil {
+		return rep.db
+	}
+	return r.primary
+}
+
+// ExecContext always runs against the primary connection.
+func (r *Resolver) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return r.primary.ExecContext(ctx, query, args...)
+}
+
+// PrepareContext always prepares against the primary connection.
+func (r *Resolver) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return r.primary.PrepareContext(ctx, query)
+}
+
+// QueryContext runs against a load-balanced, healthy replica, falling back
+// to primary if none are healthy.
+func (r *Resolver) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return r.reader().QueryContext(ctx, query, args...)
+}
+
+// QueryRowContext runs against a load-balanced, healthy replica, falling
+// back to primary if none are healthy.
+func (r *Resolver) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return r.reader().QueryRowContext(ctx, query, args...)
+}
+
+// Begin always starts the transaction against the primary connection, so
+// the resulting TxDatabase is pinned to primary for its entire lifetime.
+func (r *Resolver) Begin() (*sql.Tx, error) {
+	return r.primary.Begin()
+}
+
+// BeginTx always starts the transaction against the primary connection, so
+// the resulting TxDatabase is pinned to primary for its entire lifetime.
+func (r *Resolver) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return r.primary.BeginTx(ctx, opts)
+}
@@ -0,0 +1,115 @@
+package goqu
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sync/atomic"
+)
+
+// savepointNameRe matches the identifiers Savepoint/RollbackToSavepoint/
+// ReleaseSavepoint accept. name is interpolated directly into SQL (dialects
+// don't support binding it as a parameter), so it's restricted to a safe
+// identifier shape rather than passed through unchecked.
+var savepointNameRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func validateSavepointName(name string) error {
+	if !savepointNameRe.MatchString(name) {
+		return fmt.Errorf("goqu: invalid savepoint name %q: must match %s", name, savepointNameRe.String())
+	}
+	return nil
+}
+
+// Savepoint creates a named SAVEPOINT within the current transaction, using
+// the syntax appropriate for this TxDatabase's dialect. Like ExecContext,
+// it runs through this TxDatabase's Hooks (and, by default, Logger-based
+// tracing), so instrumentation attached via Hooks sees savepoint boundaries
+// too. name must match savepointNameRe; it's rejected otherwise, since it's
+// interpolated directly into the SQL statement.
+func (td *TxDatabase) Savepoint(name string) error {
+	if err := validateSavepointName(name); err != nil {
+		return err
+	}
+	_, err := td.execOp(context.Background(), "SAVEPOINT", savepointSQL(td.dialect, name))
+	return err
+}
+
+// RollbackToSavepoint rolls the transaction back to a savepoint previously
+// created with Savepoint, undoing any statements executed since, without
+// ending the surrounding transaction.
+func (td *TxDatabase) RollbackToSavepoint(name string) error {
+	if err := validateSavepointName(name); err != nil {
+		return err
+	}
+	_, err := td.execOp(context.Background(), "ROLLBACK TO SAVEPOINT", rollbackToSavepointSQL(td.dialect, name))
+	return err
+}
+
+// ReleaseSavepoint releases a savepoint previously created with Savepoint,
+// keeping its changes as part of the surrounding transaction.
+func (td *TxDatabase) ReleaseSavepoint(name string) error {
+	if err := validateSavepointName(name); err != nil {
+		return err
+	}
+	_, err := td.execOp(context.Background(), "RELEASE SAVEPOINT", releaseSavepointSQL(td.dialect, name))
+	return err
+}
+
+// WithTx runs fn within a nested transactional boundary. Since td is already
+// inside a transaction, this opens a uniquely named SAVEPOINT instead of a
+// new transaction, and commits (releases) or rolls back to that savepoint
+// when fn returns or panics, mirroring Database.WithTx. This lets helpers
+// call WithTx without needing to know whether they are already running
+// inside a transaction, a common need when composing with migration tools.
+func (td *TxDatabase) WithTx(fn func(*TxDatabase) error) (err error) {
+	name := fmt.Sprintf("goqu_sp_%d", atomic.AddInt32(&td.savepointSeq, 1))
+	if err = td.Savepoint(name); err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = td.RollbackToSavepoint(name)
+			panic(p)
+		}
+		if err != nil {
+			if rollbackErr := td.RollbackToSavepoint(name); rollbackErr != nil {
+				err = rollbackErr
+			}
+			return
+		}
+		if releaseErr := td.ReleaseSavepoint(name); releaseErr != nil {
+			err = releaseErr
+		}
+	}()
+	return fn(td)
+}
+
+// savepointSQL returns the dialect-appropriate statement to create a
+// savepoint. SQL Server has no SAVEPOINT keyword and uses SAVE TRANSACTION
+// instead; Postgres, MySQL, and SQLite all accept the standard syntax.
+func savepointSQL(dialect, name string) string {
+	if dialect == "sqlserver" {
+		return "SAVE TRANSACTION " + name
+	}
+	return "SAVEPOINT " + name
+}
+
+// rollbackToSavepointSQL returns the dialect-appropriate statement to roll
+// back to a savepoint.
+func rollbackToSavepointSQL(dialect, name string) string {
+	if dialect == "sqlserver" {
+		return "ROLLBACK TRANSACTION " + name
+	}
+	return "ROLLBACK TO SAVEPOINT " + name
+}
+
+// releaseSavepointSQL returns the dialect-appropriate statement to release a
+// savepoint. SQL Server has no equivalent to RELEASE SAVEPOINT: its
+// SAVE TRANSACTION savepoints are implicitly released on commit, so this is
+// a no-op statement that is safe to execute.
+func releaseSavepointSQL(dialect, name string) string {
+	if dialect == "sqlserver" {
+		return "SELECT 1"
+	}
+	return "RELEASE SAVEPOINT " + name
+}
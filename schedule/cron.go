@@ -0,0 +1,129 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronField is a bitset of allowed values for a single cron field. Every
+// supported field (minute, hour, day-of-month, month, day-of-week) fits
+// within the 0-59 range, so a uint64 bitset is sufficient.
+type cronField struct {
+	bits uint64
+}
+
+func (f cronField) has(v int) bool {
+	return f.bits&(1<<uint(v)) != 0
+}
+
+type fieldRange struct {
+	min, max int
+}
+
+// cronSpec is a parsed standard 5-field cron expression: minute hour
+// day-of-month month day-of-week.
+type cronSpec struct {
+	minute, hour, dom, month, dow cronField
+	domWild, dowWild              bool
+}
+
+var fieldRanges = [5]fieldRange{
+	{0, 59}, // minute
+	{0, 23}, // hour
+	{1, 31}, // day of month
+	{1, 12}, // month
+	{0, 6},  // day of week (0 = Sunday)
+}
+
+// parseCronSpec parses a standard 5-field cron expression, supporting `*`,
+// single values, ranges (`1-5`), steps (`*/15`, `1-10/2`), and comma
+// separated lists of any of the above.
+func parseCronSpec(spec string) (cronSpec, error) {
+	parts := strings.Fields(spec)
+	if len(parts) != 5 {
+		return cronSpec{}, fmt.Errorf("expected 5 fields (minute hour dom month dow), got %d", len(parts))
+	}
+	fields := make([]cronField, 5)
+	for i, part := range parts {
+		f, err := parseCronField(part, fieldRanges[i])
+		if err != nil {
+			return cronSpec{}, fmt.Errorf("field %d (%q): %w", i+1, part, err)
+		}
+		fields[i] = f
+	}
+	return cronSpec{
+		minute:  fields[0],
+		hour:    fields[1],
+		dom:     fields[2],
+		month:   fields[3],
+		dow:     fields[4],
+		domWild: parts[2] == "*",
+		dowWild: parts[4] == "*",
+	}, nil
+}
+
+func parseCronField(part string, r fieldRange) (cronField, error) {
+	var f cronField
+	for _, term := range strings.Split(part, ",") {
+		lo, hi, step := r.min, r.max, 1
+		rangePart := term
+		if idx := strings.IndexByte(term, '/'); idx >= 0 {
+			var err error
+			step, err = strconv.Atoi(term[idx+1:])
+			if err != nil || step <= 0 {
+				return f, fmt.Errorf("invalid step in %q", term)
+			}
+			rangePart = term[:idx]
+		}
+		switch {
+		case rangePart == "*":
+			// lo, hi already default to the field's full range
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if lo, err = strconv.Atoi(bounds[0]); err != nil {
+				return f, fmt.Errorf("invalid range start in %q", term)
+			}
+			if hi, err = strconv.Atoi(bounds[1]); err != nil {
+				return f, fmt.Errorf("invalid range end in %q", term)
+			}
+		default:
+			v, err := strconv.Atoi(rangePart)
+			if err != nil {
+				return f, fmt.Errorf("invalid value %q", rangePart)
+			}
+			lo, hi = v, v
+		}
+		if lo < r.min || hi > r.max || lo > hi {
+			return f, fmt.Errorf("value out of range [%d,%d] in %q", r.min, r.max, term)
+		}
+		for v := lo; v <= hi; v += step {
+			f.bits |= 1 << uint(v)
+		}
+	}
+	return f, nil
+}
+
+// matches reports whether t falls on this cron schedule's tick. Following
+// standard cron semantics, when both day-of-month and day-of-week are
+// restricted (not `*`), a match on either is sufficient; when only one is
+// restricted, that one alone must match.
+func (s cronSpec) matches(t time.Time) bool {
+	if !s.minute.has(t.Minute()) || !s.hour.has(t.Hour()) || !s.month.has(int(t.Month())) {
+		return false
+	}
+	domMatch := s.dom.has(t.Day())
+	dowMatch := s.dow.has(int(t.Weekday()))
+	switch {
+	case s.domWild && s.dowWild:
+		return true
+	case s.domWild:
+		return dowMatch
+	case s.dowWild:
+		return domMatch
+	default:
+		return domMatch || dowMatch
+	}
+}
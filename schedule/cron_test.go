@@ -0,0 +1,122 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronSpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		wantErr bool
+	}{
+		{name: "every minute", spec: "* * * * *"},
+		{name: "specific fields", spec: "30 4 1 1 0"},
+		{name: "ranges", spec: "0-29 0-11 1-15 1-6 1-5"},
+		{name: "steps", spec: "*/15 */2 * * *"},
+		{name: "stepped range", spec: "1-10/2 * * * *"},
+		{name: "comma list", spec: "0,15,30,45 * * * *"},
+		{name: "too few fields", spec: "* * * *", wantErr: true},
+		{name: "too many fields", spec: "* * * * * *", wantErr: true},
+		{name: "out of range value", spec: "60 * * * *", wantErr: true},
+		{name: "invalid range order", spec: "10-5 * * * *", wantErr: true},
+		{name: "non-numeric value", spec: "a * * * *", wantErr: true},
+		{name: "invalid step", spec: "*/0 * * * *", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseCronSpec(tt.spec)
+			if tt.wantErr && err == nil {
+				t.Fatalf("parseCronSpec(%q) expected an error, got nil", tt.spec)
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("parseCronSpec(%q) unexpected error: %s", tt.spec, err)
+			}
+		})
+	}
+}
+
+func TestCronSpec_Matches(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+		t    time.Time
+		want bool
+	}{
+		{
+			name: "every minute always matches",
+			spec: "* * * * *",
+			t:    time.Date(2026, time.July, 27, 10, 30, 0, 0, time.UTC),
+			want: true,
+		},
+		{
+			name: "minute mismatch",
+			spec: "0 * * * *",
+			t:    time.Date(2026, time.July, 27, 10, 30, 0, 0, time.UTC),
+			want: false,
+		},
+		{
+			name: "exact match",
+			spec: "30 10 27 7 *",
+			t:    time.Date(2026, time.July, 27, 10, 30, 0, 0, time.UTC),
+			want: true,
+		},
+		{
+			name: "step match",
+			spec: "*/15 * * * *",
+			t:    time.Date(2026, time.July, 27, 10, 30, 0, 0, time.UTC),
+			want: true,
+		},
+		{
+			name: "step mismatch",
+			spec: "*/15 * * * *",
+			t:    time.Date(2026, time.July, 27, 10, 31, 0, 0, time.UTC),
+			want: false,
+		},
+		{
+			name: "dom and dow both restricted: OR semantics, dom matches",
+			// 2026-07-27 is a Monday (weekday 1); restrict dom to the 27th and
+			// dow to Friday (5) - only dom should need to match.
+			spec: "0 0 27 * 5",
+			t:    time.Date(2026, time.July, 27, 0, 0, 0, 0, time.UTC),
+			want: true,
+		},
+		{
+			name: "dom and dow both restricted: OR semantics, dow matches",
+			// restrict dom to a day that isn't the 27th, but dow to Monday (1).
+			spec: "0 0 1 * 1",
+			t:    time.Date(2026, time.July, 27, 0, 0, 0, 0, time.UTC),
+			want: true,
+		},
+		{
+			name: "dom and dow both restricted: neither matches",
+			spec: "0 0 1 * 5",
+			t:    time.Date(2026, time.July, 27, 0, 0, 0, 0, time.UTC),
+			want: false,
+		},
+		{
+			name: "only dom restricted: dow is ignored",
+			spec: "0 0 27 * *",
+			t:    time.Date(2026, time.July, 27, 0, 0, 0, 0, time.UTC),
+			want: true,
+		},
+		{
+			name: "only dow restricted: dom is ignored",
+			spec: "0 0 * * 1",
+			t:    time.Date(2026, time.July, 27, 0, 0, 0, 0, time.UTC),
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs, err := parseCronSpec(tt.spec)
+			if err != nil {
+				t.Fatalf("parseCronSpec(%q): %s", tt.spec, err)
+			}
+			if got := cs.matches(tt.t); got != tt.want {
+				t.Errorf("matches(%s) = %v, want %v", tt.t, got, tt.want)
+			}
+		})
+	}
+}
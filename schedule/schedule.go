@@ -0,0 +1,237 @@
+// Package schedule lets goqu users register queries to run on a cron
+// expression against a *goqu.Database, giving a first-class way to run
+// recurring maintenance (VACUUM ANALYZE, materialized-view refresh, cleanup
+// DELETEs) without pulling in a separate cron library.
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// scheduledRunsTable is created and queried by Scheduler, when leader
+// election is enabled, to coordinate across distributed workers sharing the
+// same database: only one worker's claim for a given job and tick will
+// succeed. See Scheduler.Migrate and Scheduler.LeaderElection.
+const scheduledRunsTable = "goqu_scheduled_runs"
+
+type job struct {
+	name    string
+	spec    cronSpec
+	fn      func(ctx context.Context, tx *goqu.TxDatabase) error
+	timeout time.Duration
+}
+
+// Scheduler runs registered jobs against a *goqu.Database on a cron
+// schedule. Each tick acquires a short-lived transaction via
+// Database.WithTx and enforces a per-job timeout. By default a Scheduler
+// assumes it's the only one running against the database, so the common
+// single-instance use case works with no setup. Call LeaderElection(true)
+// (after Migrate, to create the backing table) when running multiple
+// Scheduler instances against the same database, so only one of them
+// executes a given job for a given tick.
+type Scheduler struct {
+	db     *goqu.Database
+	logger goqu.Logger
+
+	mu             sync.Mutex
+	jobs           []*job
+	leaderElection bool
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New creates a Scheduler that will run jobs against db.
+func New(db *goqu.Database) *Scheduler {
+	return &Scheduler{db: db}
+}
+
+// Logger sets the logger used to report per-job start, success, and failure.
+func (s *Scheduler) Logger(logger goqu.Logger) {
+	s.logger = logger
+}
+
+// LeaderElection enables or disables coordinating job runs across multiple
+// Scheduler instances sharing the same database, via the goqu_scheduled_runs
+// table. It is disabled by default: a single Scheduler instance runs jobs
+// directly with no table required. Enable it only after creating that table
+// with Migrate.
+func (s *Scheduler) LeaderElection(enabled bool) {
+	s.leaderElection = enabled
+}
+
+// Migrate creates the goqu_scheduled_runs table used for leader election, if
+// it doesn't already exist. It creates the table whether or not
+// LeaderElection is enabled; a Scheduler that never enables LeaderElection
+// does not need it. Call it once, e.g. as part of your application's normal
+// migrations, before enabling LeaderElection.
+func (s *Scheduler) Migrate(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, createScheduledRunsTableSQL(s.db.Dialect()))
+	return err
+}
+
+// Add registers fn to run on the cron schedule described by spec (a
+// standard 5-field "minute hour dom month dow" expression). name identifies
+// the job in logs and in the goqu_scheduled_runs leader-election table, and
+// must be unique across jobs registered on this Scheduler.
+func (s *Scheduler) Add(spec, name string, fn func(ctx context.Context, tx *goqu.TxDatabase) error) error {
+	cs, err := parseCronSpec(spec)
+	if err != nil {
+		return fmt.Errorf("schedule: invalid cron spec %q for job %q: %w", spec, name, err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs = append(s.jobs, &job{name: name, spec: cs, fn: fn})
+	return nil
+}
+
+// AddWithTimeout is like Add but bounds each run of fn with a per-tick
+// timeout, after which the run's context is cancelled.
+func (s *Scheduler) AddWithTimeout(spec, name string, timeout time.Duration, fn func(ctx context.Context, tx *goqu.TxDatabase) error) error {
+	cs, err := parseCronSpec(spec)
+	if err != nil {
+		return fmt.Errorf("schedule: invalid cron spec %q for job %q: %w", spec, name, err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs = append(s.jobs, &job{name: name, spec: cs, fn: fn, timeout: timeout})
+	return nil
+}
+
+// Start begins running all registered jobs on their configured cron
+// schedules against ctx. Start returns immediately; jobs run asynchronously
+// until Stop is called or ctx is done.
+func (s *Scheduler) Start(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+	s.mu.Lock()
+	jobs := append([]*job(nil), s.jobs...)
+	s.mu.Unlock()
+	for _, j := range jobs {
+		s.wg.Add(1)
+		go s.run(ctx, j)
+	}
+}
+
+// Stop signals all running jobs to stop ticking and waits for any run in
+// progress to finish.
+func (s *Scheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+}
+
+func (s *Scheduler) run(ctx context.Context, j *job) {
+	defer s.wg.Done()
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			// Truncate to the minute so every Scheduler instance (each
+			// with its own ticker, started at a different wall-clock
+			// time) agrees on the same canonical tick identity: claimRun
+			// keys its UNIQUE(job_name, run_at) claim on this value, and a
+			// claim only coordinates distributed workers if they all
+			// record the same run_at for the same tick.
+			at := now.Truncate(time.Minute)
+			if j.spec.matches(at) {
+				s.runOnce(ctx, j, at)
+			}
+		}
+	}
+}
+
+func (s *Scheduler) runOnce(ctx context.Context, j *job, at time.Time) {
+	runCtx := ctx
+	if j.timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, j.timeout)
+		defer cancel()
+	}
+	s.logf("schedule: starting job %q at %s", j.name, at)
+	err := s.db.WithTx(func(tx *goqu.TxDatabase) error {
+		if s.leaderElection {
+			claimed, err := claimRun(runCtx, tx, j.name, at)
+			if err != nil {
+				return fmt.Errorf("schedule: claiming run of job %q: %w", j.name, err)
+			}
+			if !claimed {
+				s.logf("schedule: job %q already claimed by another worker for %s, skipping", j.name, at)
+				return nil
+			}
+		}
+		return j.fn(runCtx, tx)
+	})
+	if err != nil {
+		s.logf("schedule: job %q failed: %s", j.name, err)
+		return
+	}
+	s.logf("schedule: job %q finished", j.name)
+}
+
+// claimRun records this tick of job name in goqu_scheduled_runs with a
+// single INSERT relying on a UNIQUE(job_name, run_at) constraint (created by
+// Migrate) to serialize concurrent claims: the database itself guarantees
+// that only one of any number of racing inserts for the same (name, at)
+// succeeds, which a SELECT-then-INSERT (even under FOR UPDATE SKIP LOCKED)
+// cannot, since there is no existing row to lock until the first insert
+// commits. A unique-constraint violation is the expected outcome for the
+// losing side of that race, not a failure, so it's reported as claimed=false
+// rather than as an error. Callers must run claimRun inside the transaction
+// they intend to run the job's work in, so the claim and the work commit or
+// roll back together.
+func claimRun(ctx context.Context, tx *goqu.TxDatabase, name string, at time.Time) (bool, error) {
+	_, err := tx.ExecContext(ctx,
+		tx.Rebind("INSERT INTO "+scheduledRunsTable+" (job_name, run_at) VALUES (?, ?)"),
+		name, at,
+	)
+	if err == nil {
+		return true, nil
+	}
+	if isUniqueViolation(err) {
+		return false, nil
+	}
+	return false, err
+}
+
+// isUniqueViolation reports whether err looks like a unique/primary key
+// constraint violation. goqu doesn't depend on any specific driver package,
+// so this can't type-assert a driver-specific error type; it instead
+// recognizes the common wording used by Postgres, MySQL, SQLite, and SQL
+// Server drivers. Callers relying on precise detection for a single known
+// driver should check its error type directly instead.
+func isUniqueViolation(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "unique") || strings.Contains(msg, "duplicate")
+}
+
+// createScheduledRunsTableSQL returns the dialect-appropriate DDL to create
+// the goqu_scheduled_runs table used for leader election, if it doesn't
+// already exist.
+func createScheduledRunsTableSQL(dialect string) string {
+	if dialect == "sqlserver" {
+		return "IF NOT EXISTS (SELECT * FROM sysobjects WHERE name = '" + scheduledRunsTable + "' AND xtype = 'U') " +
+			"CREATE TABLE " + scheduledRunsTable + " (" +
+			"job_name VARCHAR(255) NOT NULL, run_at DATETIME2 NOT NULL, " +
+			"CONSTRAINT uq_" + scheduledRunsTable + " UNIQUE (job_name, run_at))"
+	}
+	return "CREATE TABLE IF NOT EXISTS " + scheduledRunsTable + " (" +
+		"job_name VARCHAR(255) NOT NULL, run_at TIMESTAMP NOT NULL, " +
+		"UNIQUE (job_name, run_at))"
+}
+
+func (s *Scheduler) logf(format string, v ...interface{}) {
+	if s.logger != nil {
+		s.logger.Printf(format, v...)
+	}
+}